@@ -0,0 +1,437 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/smsouls/myGoserver/model"
+)
+
+// schemaBootstrappers maps a driver name to the function that prepares its
+// schema (running migrations, creating tables, ...) before first use. Each
+// driver file registers its own entry in an init function so the
+// dialect-specific bootstrap logic lives next to the driver that needs it.
+var schemaBootstrappers = map[string]func(context.Context, *sql.DB) error{}
+
+// bookColumns lists every column of the books table, in the order scanBook
+// expects to read them.
+const bookColumns = "id, title, author, publishedDate, imageUrl, description, createdBy, createdById, " +
+	"isbn10, isbn13, authors, genre, publisher, series, volume, year, signed, onLoan, coverUrl"
+
+const (
+	listStatement   = "SELECT " + bookColumns + " FROM books ORDER BY title"
+	listByStatement = "SELECT " + bookColumns + " FROM books WHERE createdById = ? ORDER BY title"
+	getStatement    = "SELECT " + bookColumns + " FROM books WHERE id = ?"
+	insertStatement = "INSERT INTO books (title, author, publishedDate, imageUrl, description, createdBy, createdById, " +
+		"isbn10, isbn13, authors, genre, publisher, series, volume, year, signed, onLoan, coverUrl) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	deleteStatement = "DELETE FROM books WHERE id = ?"
+	updateStatement = "UPDATE books SET title = ?, author = ?, publishedDate = ?, imageUrl = ?, description = ?, " +
+		"createdBy = ?, createdById = ?, isbn10 = ?, isbn13 = ?, authors = ?, genre = ?, publisher = ?, " +
+		"series = ?, volume = ?, year = ?, signed = ?, onLoan = ?, coverUrl = ? WHERE id = ?"
+)
+
+// SQLBookDatabase is a BookDatabase backed by database/sql. It is written
+// against the parts of database/sql that every driver supports, and relies
+// on schemaBootstrappers to paper over dialect differences (AUTO_INCREMENT
+// vs AUTOINCREMENT, INT UNSIGNED vs INTEGER, ...), so the same code serves
+// MySQL, SQLite, or any other registered driver.
+type SQLBookDatabase struct {
+	conn       *sql.DB
+	driverName string
+
+	list   *sql.Stmt
+	listBy *sql.Stmt
+	insert *sql.Stmt
+	get    *sql.Stmt
+	update *sql.Stmt
+	delete *sql.Stmt
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+var _ BookDatabase = &SQLBookDatabase{}
+
+// newSQLBookDatabase opens dataSourceName with driverName, ensuring the
+// books table exists at bootstrapDataSourceName first (this is a separate
+// data source name because some drivers, like MySQL, must connect without
+// selecting a database in order to create it). ctx bounds this startup work
+// so a client can't hang forever waiting on an unreachable database.
+func newSQLBookDatabase(ctx context.Context, driverName, bootstrapDataSourceName, dataSourceName string) (*SQLBookDatabase, error) {
+	if err := ensureSchema(ctx, driverName, bootstrapDataSourceName); err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not get a connection: %v", driverName, err)
+	}
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s: could not establish a good connection: %v", driverName, err)
+	}
+
+	db := &SQLBookDatabase{
+		conn:       conn,
+		driverName: driverName,
+	}
+
+	if db.list, err = conn.PrepareContext(ctx, listStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare list: %v", driverName, err)
+	}
+
+	if db.listBy, err = conn.PrepareContext(ctx, listByStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare listBy: %v", driverName, err)
+	}
+
+	if db.get, err = conn.PrepareContext(ctx, getStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare get: %v", driverName, err)
+	}
+
+	if db.insert, err = conn.PrepareContext(ctx, insertStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare insert: %v", driverName, err)
+	}
+
+	if db.update, err = conn.PrepareContext(ctx, updateStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare update: %v", driverName, err)
+	}
+
+	if db.delete, err = conn.PrepareContext(ctx, deleteStatement); err != nil {
+		return nil, fmt.Errorf("%s: prepare delete: %v", driverName, err)
+	}
+
+	return db, nil
+}
+
+// ensureSchema opens its own connection to dataSourceName and runs the
+// schema bootstrapper registered for driverName against it.
+func ensureSchema(ctx context.Context, driverName, dataSourceName string) error {
+	bootstrap, ok := schemaBootstrappers[driverName]
+	if !ok {
+		return fmt.Errorf("%s: no schema bootstrapper registered for this driver", driverName)
+	}
+
+	conn, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return fmt.Errorf("%s: could not get a connection: %v", driverName, err)
+	}
+	defer conn.Close()
+
+	return bootstrap(ctx, conn)
+}
+
+func (db *SQLBookDatabase) Close() {
+	db.conn.Close()
+}
+
+// Ping checks that the underlying connection is reachable.
+func (db *SQLBookDatabase) Ping(ctx context.Context) error {
+	return db.conn.PingContext(ctx)
+}
+
+func scanBook(s rowScanner) (*Book, error) {
+	var (
+		id            int64
+		title         sql.NullString
+		author        sql.NullString
+		publishedDate sql.NullString
+		imageURL      sql.NullString
+		description   sql.NullString
+		createdBy     sql.NullString
+		createdByID   sql.NullString
+		isbn10        sql.NullString
+		isbn13        sql.NullString
+		authorsJSON   sql.NullString
+		genre         sql.NullString
+		publisher     sql.NullString
+		series        sql.NullString
+		volume        sql.NullInt64
+		year          sql.NullInt64
+		signed        sql.NullBool
+		onLoan        sql.NullString
+		coverURL      sql.NullString
+	)
+
+	if err := s.Scan(&id, &title, &author, &publishedDate, &imageURL,
+		&description, &createdBy, &createdByID,
+		&isbn10, &isbn13, &authorsJSON, &genre, &publisher, &series, &volume, &year, &signed, &onLoan, &coverURL); err != nil {
+		return nil, err
+	}
+
+	var authors []string
+	if authorsJSON.Valid && authorsJSON.String != "" {
+		if err := json.Unmarshal([]byte(authorsJSON.String), &authors); err != nil {
+			return nil, fmt.Errorf("sql: could not decode authors: %v", err)
+		}
+	}
+
+	book := &Book{
+		ID:            id,
+		Title:         title.String,
+		Author:        author.String,
+		PublishedDate: publishedDate.String,
+		ImageURL:      imageURL.String,
+		Description:   description.String,
+		CreatedBy:     createdBy.String,
+		CreatedByID:   createdByID.String,
+		ISBN10:        isbn10.String,
+		ISBN13:        isbn13.String,
+		Authors:       authors,
+		Genre:         genre.String,
+		Publisher:     publisher.String,
+		Series:        series.String,
+		Volume:        int(volume.Int64),
+		Year:          int(year.Int64),
+		Signed:        signed.Bool,
+		OnLoan:        onLoan.String,
+		CoverURL:      coverURL.String,
+	}
+
+	return book, nil
+}
+
+// encodeAuthors marshals b.Authors to the JSON array stored in the authors
+// column, or nil if there are no co-authors to record.
+func encodeAuthors(b *Book) (interface{}, error) {
+	if len(b.Authors) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(b.Authors)
+	if err != nil {
+		return nil, fmt.Errorf("sql: could not encode authors: %v", err)
+	}
+
+	return string(encoded), nil
+}
+
+// execAffectingOneRow runs stmt and confirms it affected exactly one row.
+// rowsAffected == 0 means the targeted row doesn't exist, which callers
+// (UpdateBook, DeleteBook) should treat the same as a failed lookup.
+func execAffectingOneRow(ctx context.Context, stmt *sql.Stmt, args ...interface{}) (sql.Result, error) {
+	r, err := stmt.ExecContext(ctx, args...)
+
+	if err != nil {
+		return r, fmt.Errorf("sql: could not execute statement: %v", err)
+	}
+
+	rowsAffected, err := r.RowsAffected()
+
+	if err != nil {
+		return r, fmt.Errorf("sql: could not get rows affected: %v", err)
+	} else if rowsAffected == 0 {
+		return r, sql.ErrNoRows
+	} else if rowsAffected != 1 {
+		return r, fmt.Errorf("sql: expected 1 row affected, got %d", rowsAffected)
+	}
+
+	return r, nil
+}
+
+func (db *SQLBookDatabase) ListBooks(ctx context.Context) ([]*Book, error) {
+	rows, err := db.list.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []*Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("sql: could not read row: %v", err)
+		}
+
+		books = append(books, book)
+	}
+
+	return books, nil
+}
+
+func (db *SQLBookDatabase) ListBooksCreatedBy(ctx context.Context, userID string) ([]*Book, error) {
+	if userID == "" {
+		return db.ListBooks(ctx)
+	}
+
+	rows, err := db.listBy.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []*Book
+
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("sql: could not read row: %v", err)
+		}
+
+		books = append(books, book)
+	}
+
+	return books, nil
+}
+
+func (db *SQLBookDatabase) GetBook(ctx context.Context, id int64) (*Book, error) {
+	book, err := scanBook(db.get.QueryRowContext(ctx, id))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sql: could not find book with id %d: %w", id, sql.ErrNoRows)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql: could not get book: %v", err)
+	}
+
+	return book, nil
+}
+
+func (db *SQLBookDatabase) AddBook(ctx context.Context, b *Book) (id int64, err error) {
+	authors, err := encodeAuthors(b)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := execAffectingOneRow(ctx, db.insert, b.Title, b.Author, b.PublishedDate, b.ImageURL, b.Description, b.CreatedBy, b.CreatedByID,
+		b.ISBN10, b.ISBN13, authors, b.Genre, b.Publisher, b.Series, b.Volume, b.Year, b.Signed, b.OnLoan, b.CoverURL)
+
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertID, err := r.LastInsertId()
+
+	if err != nil {
+		return 0, fmt.Errorf("sql: could not get last insert ID: %v", err)
+	}
+
+	return lastInsertID, nil
+}
+
+func (db *SQLBookDatabase) DeleteBook(ctx context.Context, id int64) error {
+	if id == 0 {
+		return &ValidationError{Msg: "sql: book with unassigned ID passed into DeleteBook"}
+	}
+
+	_, err := execAffectingOneRow(ctx, db.delete, id)
+
+	return err
+}
+
+func (db *SQLBookDatabase) UpdateBook(ctx context.Context, b *Book) error {
+	if b.ID == 0 {
+		return &ValidationError{Msg: "sql: book with unassigned ID passed into UpdateBook"}
+	}
+
+	authors, err := encodeAuthors(b)
+	if err != nil {
+		return err
+	}
+
+	_, err = execAffectingOneRow(ctx, db.update, b.Title, b.Author, b.PublishedDate, b.ImageURL, b.Description, b.CreatedBy, b.CreatedByID,
+		b.ISBN10, b.ISBN13, authors, b.Genre, b.Publisher, b.Series, b.Volume, b.Year, b.Signed, b.OnLoan, b.CoverURL, b.ID)
+	return err
+}
+
+// sortableColumns whitelists the columns SearchBooks may order by, since
+// BookQuery.SortBy is caller-controlled and must never be interpolated
+// unchecked into SQL.
+var sortableColumns = map[string]bool{
+	"id": true, "title": true, "author": true, "publishedDate": true,
+	"genre": true, "year": true, "series": true, "volume": true,
+}
+
+// SearchBooks implements pagination, exact-match filtering, and (on MySQL)
+// full-text search over title, author, and description.
+func (db *SQLBookDatabase) SearchBooks(ctx context.Context, query BookQuery) (BookPage, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if query.Author != "" {
+		conditions = append(conditions, "author = ?")
+		args = append(args, query.Author)
+	}
+
+	if query.Genre != "" {
+		conditions = append(conditions, "genre = ?")
+		args = append(args, query.Genre)
+	}
+
+	if query.Year != 0 {
+		conditions = append(conditions, "year = ?")
+		args = append(args, query.Year)
+	}
+
+	if query.Match != "" {
+		if db.driverName != "mysql" {
+			return BookPage{}, &ValidationError{Msg: fmt.Sprintf("sql: full-text search is only supported on mysql, got driver %q", db.driverName)}
+		}
+
+		conditions = append(conditions, "MATCH(title, author, description) AGAINST (? IN NATURAL LANGUAGE MODE)")
+		args = append(args, query.Match)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sortBy := "title"
+	if query.SortBy != "" {
+		if !sortableColumns[query.SortBy] {
+			return BookPage{}, &ValidationError{Msg: fmt.Sprintf("sql: cannot sort by column %q", query.SortBy)}
+		}
+		sortBy = query.SortBy
+	}
+
+	order := "ASC"
+	if query.Desc {
+		order = "DESC"
+	}
+
+	var total int64
+	countRow := db.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM books "+where, args...)
+	if err := countRow.Scan(&total); err != nil {
+		return BookPage{}, fmt.Errorf("sql: could not count books: %v", err)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	selectSQL := fmt.Sprintf("SELECT %s FROM books %s ORDER BY %s %s LIMIT ? OFFSET ?", bookColumns, where, sortBy, order)
+
+	rows, err := db.conn.QueryContext(ctx, selectSQL, append(args, limit, query.Offset)...)
+	if err != nil {
+		return BookPage{}, fmt.Errorf("sql: could not search books: %v", err)
+	}
+	defer rows.Close()
+
+	var books []*Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return BookPage{}, fmt.Errorf("sql: could not read row: %v", err)
+		}
+
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		return BookPage{}, err
+	}
+
+	return BookPage{Items: books, Total: total}, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	. "github.com/smsouls/myGoserver/model"
+	"github.com/smsouls/myGoserver/server"
+)
+
+func main() {
+	store := flag.String("store", "mysql", "storage backend to use: mysql or sqlite")
+	addr := flag.String("addr", ":8080", "address to serve the bookshelf API on")
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		runMigrateCommand(flag.Args()[1:])
+		return
+	}
+
+	bootstrapCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var (
+		database BookDatabase
+		err      error
+	)
+
+	switch *store {
+	case "mysql":
+		database, err = newMySQLDB(bootstrapCtx, MySQLConfig{Username: "root", Password: "12345678", Port: 3306, Host: "127.0.0.1"})
+	case "sqlite":
+		database, err = newSQLiteDB(bootstrapCtx, SQLiteConfig{Path: "library.db"})
+	default:
+		log.Fatalf("unknown --store value %q: must be mysql or sqlite", *store)
+	}
+
+	if err != nil {
+		log.Fatalf("could not connect to %s database: %v", *store, err)
+	}
+	defer database.Close()
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: server.New(database).Handler(),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("listening on %s", *addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("serve: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("shutdown: %v", err)
+	}
+}
+
+// runMigrateCommand implements the "migrate up|down|status" subcommands.
+// Migrations only apply to the MySQL backend; see bootstrapSQLiteSchema.
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		log.Fatalf("usage: migrate <up|down|status>")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	config := MySQLConfig{Username: "root", Password: "12345678", Port: 3306, Host: "127.0.0.1"}
+	dsn, err := config.dataStoreName("library")
+	if err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		log.Fatalf("migrate: could not connect: %v", err)
+	}
+	defer conn.Close()
+
+	switch args[0] {
+	case "up":
+		if err := Migrate(ctx, conn, migrationFiles); err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Println("migrations applied")
+
+	case "down":
+		if err := Down(ctx, conn, migrationFiles); err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Println("last migration rolled back")
+
+	case "status":
+		statuses, err := Status(ctx, conn, migrationFiles)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%d\t%s\t%s\n", s.Version, s.Name, state)
+		}
+
+	default:
+		log.Fatalf("usage: migrate <up|down|status>")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smsouls/myGoserver/model"
+)
+
+// TestAddAndGetBook is an integration test for the add/get round trip that
+// used to run, with hard-coded data, straight out of main.
+func TestAddAndGetBook(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := newSQLiteDB(ctx, SQLiteConfig{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("newSQLiteDB: %v", err)
+	}
+	defer db.Close()
+
+	book := &Book{
+		Title:         "小朋友",
+		Author:        "小朋友",
+		PublishedDate: "2018-06-28",
+		ImageURL:      "http://www.baidu.com",
+		Description:   "哈哈啊哈哈哈哈啊",
+		CreatedBy:     "tiny",
+		CreatedByID:   "110",
+	}
+
+	id, err := db.AddBook(ctx, book)
+	if err != nil {
+		t.Fatalf("AddBook: %v", err)
+	}
+
+	got, err := db.GetBook(ctx, id)
+	if err != nil {
+		t.Fatalf("GetBook: %v", err)
+	}
+
+	if got.Title != book.Title || got.Author != book.Author || got.Description != book.Description {
+		t.Errorf("GetBook(%d) = %+v, want fields matching %+v", id, got, book)
+	}
+}
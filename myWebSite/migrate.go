@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaMigrationsTable tracks which migrations have already been applied.
+const schemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INT UNSIGNED NOT NULL PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// migration is a single numbered "NNN-name.sql" file out of the migrations
+// directory.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// MigrationStatus reports whether a single migration has been applied.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+// loadMigrations reads every forward migration ("*.sql", excluding
+// "*.down.sql" rollback files) out of fsys, ordered by leading version
+// number.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: could not read migrations directory: %v", err)
+	}
+
+	var migrations []migration
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+
+		version, err := parseVersion(name)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := fs.ReadFile(fsys, "migrations/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: could not read %s: %v", name, err)
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, sql: string(contents)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	return migrations, nil
+}
+
+func parseVersion(name string) (int, error) {
+	prefix, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return 0, fmt.Errorf("migrate: %s does not start with a numeric version", name)
+	}
+
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migrate: %s does not start with a numeric version: %v", name, err)
+	}
+
+	return version, nil
+}
+
+// appliedVersions returns the set of migration versions already recorded in
+// schema_migrations, creating that table first if this is the first run.
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	if _, err := db.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return nil, fmt.Errorf("migrate: could not create schema_migrations table: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: could not read schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("migrate: could not scan schema_migrations row: %v", err)
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// Migrate applies every migration in fsys that is not yet recorded in the
+// schema_migrations table, in version order, each inside its own
+// transaction.
+func Migrate(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migrate: %s: %v", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return fmt.Errorf("could not apply migration: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES (?)", m.version); err != nil {
+		return fmt.Errorf("could not record migration: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// Status reports the apply state of every migration in fsys.
+func Status(ctx context.Context, db *sql.DB, fsys fs.FS) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = MigrationStatus{Version: m.version, Name: m.name, Applied: applied[m.version]}
+	}
+
+	return statuses, nil
+}
+
+// Down rolls back the most recently applied migration by running its
+// matching "<version>-name.down.sql" file. It is an error if that migration
+// has no down file or nothing has been applied yet.
+func Down(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var last *migration
+	for i := range migrations {
+		if applied[migrations[i].version] {
+			last = &migrations[i]
+		}
+	}
+
+	if last == nil {
+		return fmt.Errorf("migrate: no applied migrations to roll back")
+	}
+
+	downName := "migrations/" + strings.TrimSuffix(last.name, ".sql") + ".down.sql"
+	downSQL, err := fs.ReadFile(fsys, downName)
+	if err != nil {
+		return fmt.Errorf("migrate: no down migration for %s: %v", last.name, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: could not begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(downSQL)); err != nil {
+		return fmt.Errorf("migrate: could not apply down migration: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", last.version); err != nil {
+		return fmt.Errorf("migrate: could not unrecord migration: %v", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newMigrationTestFS returns a fake migrations directory with two versioned
+// migrations, each with a down file, using SQLite-compatible DDL so the
+// migration runner can be exercised without a live MySQL server.
+func newMigrationTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/001-create-items.sql":      {Data: []byte(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)`)},
+		"migrations/001-create-items.down.sql": {Data: []byte(`DROP TABLE items`)},
+		"migrations/002-add-price.sql":         {Data: []byte(`ALTER TABLE items ADD COLUMN price INTEGER`)},
+		"migrations/002-add-price.down.sql":    {Data: []byte(`ALTER TABLE items DROP COLUMN price`)},
+	}
+}
+
+// newMigrationTestDB returns an in-memory SQLite database restricted to a
+// single connection, so the whole test sees one consistent database without
+// needing a shared-cache DSN.
+func newMigrationTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	db.SetMaxOpenConns(1)
+
+	return db
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+	}{
+		{name: "001-create-items.sql", version: 1},
+		{name: "042-add-column.sql", version: 42},
+		{name: "no-version-prefix.sql", wantErr: true},
+		{name: "abc-not-numeric.sql", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, err := parseVersion(test.name)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("parseVersion(%q) = %d, want an error", test.name, version)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseVersion(%q): %v", test.name, err)
+			}
+
+			if version != test.version {
+				t.Errorf("parseVersion(%q) = %d, want %d", test.name, version, test.version)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsOrdersByVersionAndSkipsDownFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/010-second.sql":      {Data: []byte(`SELECT 1`)},
+		"migrations/010-second.down.sql": {Data: []byte(`SELECT 1`)},
+		"migrations/002-first.sql":       {Data: []byte(`SELECT 1`)},
+	}
+
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("len(migrations) = %d, want 2", len(migrations))
+	}
+
+	if migrations[0].version != 2 || migrations[1].version != 10 {
+		t.Errorf("migrations = %+v, want versions [2, 10]", migrations)
+	}
+}
+
+func TestMigrateAppliesPendingMigrationsIdempotently(t *testing.T) {
+	ctx := context.Background()
+	db := newMigrationTestDB(t)
+	fsys := newMigrationTestFS()
+
+	if err := Migrate(ctx, db, fsys); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO items (name, price) VALUES ('book', 10)`); err != nil {
+		t.Fatalf("both migrations should have applied, but: %v", err)
+	}
+
+	// Running Migrate again should be a no-op, not an error or a duplicate
+	// application.
+	if err := Migrate(ctx, db, fsys); err != nil {
+		t.Fatalf("Migrate (second run): %v", err)
+	}
+}
+
+func TestStatusReportsWhichMigrationsAreApplied(t *testing.T) {
+	ctx := context.Background()
+	db := newMigrationTestDB(t)
+	fsys := newMigrationTestFS()
+
+	// Apply only the first migration directly, so Status sees a mix of
+	// applied and pending migrations.
+	single := fstest.MapFS{"migrations/001-create-items.sql": fsys["migrations/001-create-items.sql"]}
+	if err := Migrate(ctx, db, single); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	statuses, err := Status(ctx, db, fsys)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	if len(statuses) != 2 {
+		t.Fatalf("len(statuses) = %d, want 2", len(statuses))
+	}
+
+	if !statuses[0].Applied {
+		t.Errorf("statuses[0] = %+v, want Applied = true", statuses[0])
+	}
+
+	if statuses[1].Applied {
+		t.Errorf("statuses[1] = %+v, want Applied = false", statuses[1])
+	}
+}
+
+func TestDownRollsBackTheLastAppliedMigration(t *testing.T) {
+	ctx := context.Background()
+	db := newMigrationTestDB(t)
+	fsys := newMigrationTestFS()
+
+	if err := Migrate(ctx, db, fsys); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if err := Down(ctx, db, fsys); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO items (name, price) VALUES ('book', 10)`); err == nil {
+		t.Fatal("price column should have been rolled back by Down, but the insert succeeded")
+	}
+
+	statuses, err := Status(ctx, db, fsys)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	if statuses[1].Applied {
+		t.Errorf("statuses[1] = %+v, want Applied = false after Down", statuses[1])
+	}
+}
+
+func TestDownErrorsWithNothingApplied(t *testing.T) {
+	ctx := context.Background()
+	db := newMigrationTestDB(t)
+	fsys := newMigrationTestFS()
+
+	err := Down(ctx, db, fsys)
+	if err == nil {
+		t.Fatal("Down with no applied migrations should error")
+	}
+
+	if !strings.Contains(err.Error(), "no applied migrations") {
+		t.Errorf("Down error = %v, want it to mention no applied migrations", err)
+	}
+}
+
+func TestDownErrorsWithNoDownFile(t *testing.T) {
+	ctx := context.Background()
+	db := newMigrationTestDB(t)
+	fsys := fstest.MapFS{
+		"migrations/001-create-items.sql": {Data: []byte(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)`)},
+	}
+
+	if err := Migrate(ctx, db, fsys); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if err := Down(ctx, db, fsys); err == nil {
+		t.Fatal("Down with no down file for the applied migration should error")
+	}
+}
@@ -0,0 +1,106 @@
+// Package model holds the data types shared by every BookDatabase backend.
+package model
+
+import "context"
+
+// Book holds metadata about a library book.
+type Book struct {
+	ID            int64
+	Title         string
+	Author        string
+	PublishedDate string
+	ImageURL      string
+	Description   string
+	CreatedBy     string
+	CreatedByID   string
+
+	ISBN10    string
+	ISBN13    string
+	Authors   []string
+	Genre     string
+	Publisher string
+	Series    string
+	Volume    int
+	Year      int
+	Signed    bool
+	OnLoan    string
+	CoverURL  string
+}
+
+// BookQuery describes a SearchBooks request: pagination, sorting,
+// exact-match filters, and an optional full-text search term.
+type BookQuery struct {
+	// Limit and Offset page through the result set. Limit <= 0 means the
+	// backend's default page size is used.
+	Limit  int
+	Offset int
+
+	// SortBy is the column to order results by (e.g. "title", "year"). An
+	// empty SortBy sorts by title, matching ListBooks.
+	SortBy string
+	Desc   bool
+
+	// Author, Genre, and Year, when non-zero, restrict results to an exact
+	// match on that column.
+	Author string
+	Genre  string
+	Year   int
+
+	// Match, when set, runs a full-text search over title, author, and
+	// description.
+	Match string
+}
+
+// BookPage is one page of a SearchBooks result, along with the total number
+// of books matching the query across all pages.
+type BookPage struct {
+	Items []*Book
+	Total int64
+}
+
+// ValidationError indicates a request was rejected before it ever reached
+// storage, e.g. a missing required field or an unrecognized sort column.
+// Callers can distinguish it from other errors with errors.As to surface a
+// 400 instead of a 500.
+type ValidationError struct {
+	Msg string
+}
+
+func (e *ValidationError) Error() string { return e.Msg }
+
+// BookDatabase defines the set of operations a storage backend must support
+// in order to serve the bookshelf application. Each concrete backend (mysql,
+// sqlite, ...) implements this interface so callers can be agnostic of which
+// driver actually stores the data. Every method but Close takes a
+// context.Context so callers can propagate request deadlines and
+// cancellation down to the underlying driver.
+type BookDatabase interface {
+	// ListBooks returns a list of books, ordered by title.
+	ListBooks(ctx context.Context) ([]*Book, error)
+
+	// ListBooksCreatedBy returns a list of books, ordered by title, filtered
+	// by the user who created the book.
+	ListBooksCreatedBy(ctx context.Context, userID string) ([]*Book, error)
+
+	// GetBook retrieves a book by its ID.
+	GetBook(ctx context.Context, id int64) (*Book, error)
+
+	// AddBook saves a given book, assigning it a new ID.
+	AddBook(ctx context.Context, b *Book) (id int64, err error)
+
+	// DeleteBook removes a given book by its ID.
+	DeleteBook(ctx context.Context, id int64) error
+
+	// UpdateBook updates the entry for a given book.
+	UpdateBook(ctx context.Context, b *Book) error
+
+	// SearchBooks returns a page of books matching query, along with the
+	// total number of matches across all pages.
+	SearchBooks(ctx context.Context, query BookQuery) (BookPage, error)
+
+	// Ping checks that the database is reachable, for use in liveness checks.
+	Ping(ctx context.Context) error
+
+	// Close closes the database, freeing up any available resources.
+	Close()
+}
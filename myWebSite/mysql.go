@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/dialers/mysql"
+	"github.com/go-sql-driver/mysql"
+	. "github.com/smsouls/myGoserver/model"
+)
+
+func init() {
+	schemaBootstrappers["mysql"] = bootstrapMySQLSchema
+}
+
+// bootstrapMySQLSchema creates the library database if needed and brings its
+// schema up to date by applying every pending migration in migrationFiles.
+func bootstrapMySQLSchema(ctx context.Context, conn *sql.DB) error {
+	if _, err := conn.ExecContext(ctx, `CREATE DATABASE IF NOT EXISTS library DEFAULT CHARACTER SET = 'utf8' DEFAULT COLLATE 'utf8_general_ci'`); err != nil {
+		return fmt.Errorf("mysql: could not create database: %v", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, `USE library`); err != nil {
+		return fmt.Errorf("mysql: could not select database: %v", err)
+	}
+
+	if err := Migrate(ctx, conn, migrationFiles); err != nil {
+		return fmt.Errorf("mysql: could not apply migrations: %v", err)
+	}
+
+	return nil
+}
+
+// MySQLTLSConfig configures TLS for a direct TCP connection to MySQL.
+type MySQLTLSConfig struct {
+	// CAFile, if set, is a PEM-encoded CA certificate used to verify the
+	// server. Leave empty to trust the system root CAs.
+	CAFile string
+
+	// CertFile and KeyFile, if set, are a PEM-encoded client certificate and
+	// private key presented for mutual TLS.
+	CertFile string
+	KeyFile  string
+
+	// InsecureSkipVerify disables server certificate verification. Only
+	// useful for local testing against a self-signed certificate.
+	InsecureSkipVerify bool
+}
+
+// tlsConfigName is the name MySQLConfig registers its *tls.Config under via
+// mysql.RegisterTLSConfig, referenced from the DSN as "&tls=library".
+const tlsConfigName = "library"
+
+// MySQLConfig holds the information needed to connect to a MySQL database.
+// At most one of UnixSocket or CloudSQLInstance may be set; leaving both
+// empty connects over plain TCP to Host:Port.
+type MySQLConfig struct {
+	Username, Password string
+
+	Host string
+
+	Port int
+
+	UnixSocket string
+
+	// CloudSQLInstance, when set, connects via the cloudsql-proxy driver to
+	// a Cloud SQL instance, e.g. "project:region:instance".
+	CloudSQLInstance string
+
+	// TLS, when set, enables TLS on a direct TCP connection. It is invalid
+	// alongside UnixSocket or CloudSQLInstance.
+	TLS *MySQLTLSConfig
+}
+
+func (c *MySQLConfig) validate() error {
+	tcp := c.Host != "" || c.Port != 0
+
+	if c.UnixSocket != "" && c.CloudSQLInstance != "" {
+		return errors.New("mysql: UnixSocket and CloudSQLInstance are mutually exclusive")
+	}
+
+	if tcp && c.UnixSocket != "" {
+		return errors.New("mysql: Host/Port and UnixSocket are mutually exclusive")
+	}
+
+	if tcp && c.CloudSQLInstance != "" {
+		return errors.New("mysql: Host/Port and CloudSQLInstance are mutually exclusive")
+	}
+
+	if c.TLS != nil && (c.UnixSocket != "" || c.CloudSQLInstance != "") {
+		return errors.New("mysql: TLS is only supported over a direct TCP connection")
+	}
+
+	return nil
+}
+
+// registerTLSConfig builds a *tls.Config from c.TLS and registers it with
+// the mysql driver under tlsConfigName.
+func (c *MySQLConfig) registerTLSConfig() error {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.TLS.InsecureSkipVerify}
+
+	if c.TLS.CAFile != "" {
+		pem, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return fmt.Errorf("mysql: could not read CA cert %s: %v", c.TLS.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("mysql: could not parse CA cert %s", c.TLS.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.TLS.CertFile != "" || c.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return fmt.Errorf("mysql: could not load client cert/key: %v", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if err := mysql.RegisterTLSConfig(tlsConfigName, tlsConfig); err != nil {
+		return fmt.Errorf("mysql: could not register TLS config: %v", err)
+	}
+
+	return nil
+}
+
+func (c *MySQLConfig) dataStoreName(databaseName string) (string, error) {
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+
+	var cred string
+
+	if c.Username != "" {
+		cred = c.Username
+		if c.Password != "" {
+			cred = cred + ":" + c.Password
+		}
+		cred = cred + "@"
+	}
+
+	if c.UnixSocket != "" {
+		return fmt.Sprintf("%sunix(%s)/%s", cred, c.UnixSocket, databaseName), nil
+	}
+
+	if c.CloudSQLInstance != "" {
+		return fmt.Sprintf("%scloudsql(%s)/%s", cred, c.CloudSQLInstance, databaseName), nil
+	}
+
+	dsn := fmt.Sprintf("%stcp([%s]:%d)/%s?parseTime=true&charset=utf8mb4", cred, c.Host, c.Port, databaseName)
+
+	if c.TLS != nil {
+		if err := c.registerTLSConfig(); err != nil {
+			return "", err
+		}
+
+		dsn += "&tls=" + tlsConfigName
+	}
+
+	return dsn, nil
+}
+
+// newMySQLDB creates a BookDatabase backed by MySQL, creating the library
+// database and books table first if they do not already exist. ctx bounds
+// that startup work, so a caller can enforce a bootstrap timeout.
+func newMySQLDB(ctx context.Context, config MySQLConfig) (BookDatabase, error) {
+	bootstrapDSN, err := config.dataStoreName("")
+	if err != nil {
+		return nil, err
+	}
+
+	dsn, err := config.dataStoreName("library")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := newSQLBookDatabase(ctx, "mysql", bootstrapDSN, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.conn.SetMaxOpenConns(25)
+	db.conn.SetMaxIdleConns(25)
+	db.conn.SetConnMaxLifetime(5 * time.Minute)
+
+	return db, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestMySQLConfigDataStoreName(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  MySQLConfig
+		wantDSN string
+		wantErr bool
+	}{
+		{
+			name:    "tcp",
+			config:  MySQLConfig{Host: "db.example.com", Port: 3306},
+			wantDSN: "tcp([db.example.com]:3306)/library?parseTime=true&charset=utf8mb4",
+		},
+		{
+			name:    "unix socket",
+			config:  MySQLConfig{Username: "root", Password: "hunter2", UnixSocket: "/cloudsql/proj:region:inst"},
+			wantDSN: "root:hunter2@unix(/cloudsql/proj:region:inst)/library",
+		},
+		{
+			name:    "cloud sql instance",
+			config:  MySQLConfig{CloudSQLInstance: "proj:region:inst"},
+			wantDSN: "cloudsql(proj:region:inst)/library",
+		},
+		{
+			name:    "tcp and unix socket are mutually exclusive",
+			config:  MySQLConfig{Host: "db.example.com", UnixSocket: "/cloudsql/proj:region:inst"},
+			wantErr: true,
+		},
+		{
+			name:    "tcp and cloud sql instance are mutually exclusive",
+			config:  MySQLConfig{Port: 3306, CloudSQLInstance: "proj:region:inst"},
+			wantErr: true,
+		},
+		{
+			name:    "unix socket and cloud sql instance are mutually exclusive",
+			config:  MySQLConfig{UnixSocket: "/cloudsql/proj:region:inst", CloudSQLInstance: "proj:region:inst"},
+			wantErr: true,
+		},
+		{
+			name:    "tls and unix socket are mutually exclusive",
+			config:  MySQLConfig{UnixSocket: "/cloudsql/proj:region:inst", TLS: &MySQLTLSConfig{InsecureSkipVerify: true}},
+			wantErr: true,
+		},
+		{
+			name:    "tls and cloud sql instance are mutually exclusive",
+			config:  MySQLConfig{CloudSQLInstance: "proj:region:inst", TLS: &MySQLTLSConfig{InsecureSkipVerify: true}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dsn, err := test.config.dataStoreName("library")
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("dataStoreName() = %q, want an error", dsn)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("dataStoreName(): %v", err)
+			}
+
+			if dsn != test.wantDSN {
+				t.Errorf("dataStoreName() = %q, want %q", dsn, test.wantDSN)
+			}
+		})
+	}
+}
+
+// TestMySQLConfigDataStoreNameTLS checks that a TCP connection with TLS
+// configured both appends "&tls=library" to the DSN and actually registers
+// that name with the driver - mysql.ParseDSN rejects an unregistered TLS
+// config name, so a DSN that parses back out confirms registerTLSConfig ran.
+func TestMySQLConfigDataStoreNameTLS(t *testing.T) {
+	config := MySQLConfig{
+		Host: "db.example.com",
+		Port: 3306,
+		TLS:  &MySQLTLSConfig{InsecureSkipVerify: true},
+	}
+
+	dsn, err := config.dataStoreName("library")
+	if err != nil {
+		t.Fatalf("dataStoreName(): %v", err)
+	}
+
+	if !strings.HasSuffix(dsn, "&tls="+tlsConfigName) {
+		t.Fatalf("dataStoreName() = %q, want it to end with %q", dsn, "&tls="+tlsConfigName)
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("mysql.ParseDSN(%q): %v (registerTLSConfig likely never ran)", dsn, err)
+	}
+
+	if cfg.TLSConfig != tlsConfigName {
+		t.Errorf("cfg.TLSConfig = %q, want %q", cfg.TLSConfig, tlsConfigName)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smsouls/myGoserver/model"
+)
+
+func newTestSearchDB(t *testing.T) BookDatabase {
+	t.Helper()
+
+	ctx := context.Background()
+	db, err := newSQLiteDB(ctx, SQLiteConfig{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("newSQLiteDB: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	books := []*Book{
+		{Title: "A Book", Author: "Alice", Genre: "fiction", Year: 2000},
+		{Title: "B Book", Author: "Bob", Genre: "fiction", Year: 2010},
+		{Title: "C Book", Author: "Alice", Genre: "nonfiction", Year: 2020},
+	}
+
+	for _, b := range books {
+		if _, err := db.AddBook(ctx, b); err != nil {
+			t.Fatalf("AddBook(%+v): %v", b, err)
+		}
+	}
+
+	return db
+}
+
+func TestSearchBooksPagination(t *testing.T) {
+	db := newTestSearchDB(t)
+
+	page, err := db.SearchBooks(context.Background(), BookQuery{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("SearchBooks: %v", err)
+	}
+
+	if page.Total != 3 {
+		t.Errorf("Total = %d, want 3", page.Total)
+	}
+
+	if len(page.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(page.Items))
+	}
+
+	if page.Items[0].Title != "B Book" {
+		t.Errorf("Items[0].Title = %q, want %q", page.Items[0].Title, "B Book")
+	}
+}
+
+func TestSearchBooksFilters(t *testing.T) {
+	db := newTestSearchDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query BookQuery
+		want  []string
+	}{
+		{"author", BookQuery{Author: "Alice"}, []string{"A Book", "C Book"}},
+		{"genre", BookQuery{Genre: "fiction"}, []string{"A Book", "B Book"}},
+		{"year", BookQuery{Year: 2010}, []string{"B Book"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := db.SearchBooks(ctx, test.query)
+			if err != nil {
+				t.Fatalf("SearchBooks(%+v): %v", test.query, err)
+			}
+
+			if len(page.Items) != len(test.want) {
+				t.Fatalf("len(Items) = %d, want %d", len(page.Items), len(test.want))
+			}
+
+			for i, title := range test.want {
+				if page.Items[i].Title != title {
+					t.Errorf("Items[%d].Title = %q, want %q", i, page.Items[i].Title, title)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchBooksRejectsUnknownSortColumn(t *testing.T) {
+	db := newTestSearchDB(t)
+
+	_, err := db.SearchBooks(context.Background(), BookQuery{SortBy: "description; DROP TABLE books"})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("SearchBooks with bad SortBy: got err = %v, want a *ValidationError", err)
+	}
+}
+
+func TestSearchBooksRejectsFullTextSearchOnSQLite(t *testing.T) {
+	db := newTestSearchDB(t)
+
+	_, err := db.SearchBooks(context.Background(), BookQuery{Match: "book"})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("SearchBooks with Match on sqlite: got err = %v, want a *ValidationError", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package server exposes a model.BookDatabase over HTTP as JSON.
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/smsouls/myGoserver/model"
+)
+
+// Server serves the bookshelf API backed by a BookDatabase.
+type Server struct {
+	db model.BookDatabase
+}
+
+// New returns a Server backed by db.
+func New(db model.BookDatabase) *Server {
+	return &Server{db: db}
+}
+
+// Handler returns the http.Handler for the bookshelf API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/books", s.handleBooks)
+	mux.HandleFunc("/books/search", s.searchBooks)
+	mux.HandleFunc("/books/", s.handleBook)
+	return mux
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if err := s.db.Ping(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleBooks serves GET /books (optionally filtered by ?createdBy=...) and
+// POST /books.
+func (s *Server) handleBooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listBooks(w, r)
+	case http.MethodPost:
+		s.createBook(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listBooks(w http.ResponseWriter, r *http.Request) {
+	books, err := s.db.ListBooksCreatedBy(r.Context(), r.URL.Query().Get("createdBy"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, books)
+}
+
+func (s *Server) createBook(w http.ResponseWriter, r *http.Request) {
+	var book model.Book
+	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !requireTitle(w, &book) {
+		return
+	}
+
+	id, err := s.db.AddBook(r.Context(), &book)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	book.ID = id
+	writeJSON(w, http.StatusCreated, &book)
+}
+
+// requireTitle writes a 400 and returns false if book has no title.
+func requireTitle(w http.ResponseWriter, book *model.Book) bool {
+	if book.Title == "" {
+		http.Error(w, "title is required", http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// searchBooks serves GET /books/search, which pages through and filters
+// books per a BookQuery built from the request's query parameters: limit,
+// offset, sortBy, desc, author, genre, year, and match.
+func (s *Server) searchBooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query, err := parseBookQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := s.db.SearchBooks(r.Context(), query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// parseBookQuery builds a model.BookQuery from URL query parameters.
+func parseBookQuery(q url.Values) (model.BookQuery, error) {
+	query := model.BookQuery{
+		SortBy: q.Get("sortBy"),
+		Author: q.Get("author"),
+		Genre:  q.Get("genre"),
+		Match:  q.Get("match"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return model.BookQuery{}, fmt.Errorf("invalid limit: %v", err)
+		}
+		query.Limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return model.BookQuery{}, fmt.Errorf("invalid offset: %v", err)
+		}
+		query.Offset = offset
+	}
+
+	if v := q.Get("year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			return model.BookQuery{}, fmt.Errorf("invalid year: %v", err)
+		}
+		query.Year = year
+	}
+
+	if v := q.Get("desc"); v != "" {
+		desc, err := strconv.ParseBool(v)
+		if err != nil {
+			return model.BookQuery{}, fmt.Errorf("invalid desc: %v", err)
+		}
+		query.Desc = desc
+	}
+
+	return query, nil
+}
+
+// handleBook serves GET, PUT, and DELETE on /books/{id}.
+func (s *Server) handleBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/books/"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid book id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getBook(w, r, id)
+	case http.MethodPut:
+		s.updateBook(w, r, id)
+	case http.MethodDelete:
+		s.deleteBook(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) getBook(w http.ResponseWriter, r *http.Request, id int64) {
+	book, err := s.db.GetBook(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, book)
+}
+
+func (s *Server) updateBook(w http.ResponseWriter, r *http.Request, id int64) {
+	var book model.Book
+	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	book.ID = id
+
+	if !requireTitle(w, &book) {
+		return
+	}
+
+	if err := s.db.UpdateBook(r.Context(), &book); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &book)
+}
+
+func (s *Server) deleteBook(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := s.db.DeleteBook(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps a BookDatabase error to an HTTP status: 404 when the book
+// doesn't exist, 400 when the request failed validation, 500 otherwise.
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		http.Error(w, validationErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
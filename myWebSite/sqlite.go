@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/smsouls/myGoserver/model"
+)
+
+var sqliteCreateTableStatements = []string{
+	`CREATE TABLE IF NOT EXISTS books (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		title TEXT,
+		author TEXT,
+		publishedDate TEXT,
+		imageUrl TEXT,
+		description TEXT,
+		createdBy TEXT,
+		createdById TEXT,
+		isbn10 TEXT,
+		isbn13 TEXT,
+		authors TEXT,
+		genre TEXT,
+		publisher TEXT,
+		series TEXT,
+		volume INTEGER,
+		year INTEGER,
+		signed BOOLEAN NOT NULL DEFAULT 0,
+		onLoan TEXT,
+		coverUrl TEXT
+	)`,
+}
+
+func init() {
+	schemaBootstrappers["sqlite3"] = bootstrapSQLiteSchema
+}
+
+// bootstrapSQLiteSchema creates the books table directly, skipping the
+// migration runner: SQLite backs local development and tests, where a
+// throwaway, always-current schema is more useful than migration history.
+func bootstrapSQLiteSchema(ctx context.Context, conn *sql.DB) error {
+	for _, stmt := range sqliteCreateTableStatements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("sqlite3: could not create table: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// SQLiteConfig holds the information needed to connect to a SQLite database.
+type SQLiteConfig struct {
+	// Path is the location of the database file on disk. Use ":memory:" for
+	// a throwaway database, handy for local development and tests that don't
+	// need a running MySQL server.
+	Path string
+}
+
+// newSQLiteDB creates a BookDatabase backed by SQLite, creating the books
+// table first if it does not already exist.
+func newSQLiteDB(ctx context.Context, config SQLiteConfig) (BookDatabase, error) {
+	path := config.Path
+	if path == "" {
+		path = "library.db"
+	}
+
+	// ":memory:" gives each connection its own private database, which would
+	// make the schema created by the bootstrap connection invisible to the
+	// one newSQLBookDatabase keeps around. A shared cache makes every
+	// connection opened against this DSN see the same in-memory database,
+	// but that database is destroyed the instant its last connection
+	// closes - which ensureSchema's bootstrap connection does right away.
+	// Hold an anchor connection open for the life of the BookDatabase so
+	// the schema survives that handoff.
+	var anchor *sql.DB
+	if path == ":memory:" {
+		path = "file::memory:?cache=shared"
+
+		var err error
+		if anchor, err = sql.Open("sqlite3", path); err != nil {
+			return nil, fmt.Errorf("sqlite3: could not open anchor connection: %v", err)
+		}
+		if err := anchor.PingContext(ctx); err != nil {
+			anchor.Close()
+			return nil, fmt.Errorf("sqlite3: could not establish anchor connection: %v", err)
+		}
+	}
+
+	db, err := newSQLBookDatabase(ctx, "sqlite3", path, path)
+	if err != nil {
+		if anchor != nil {
+			anchor.Close()
+		}
+		return nil, err
+	}
+
+	// SQLite allows only one writer at a time; serialize on a single
+	// connection so concurrent requests don't trip over SQLITE_BUSY.
+	db.conn.SetMaxOpenConns(1)
+
+	if anchor == nil {
+		return db, nil
+	}
+
+	return &sqliteBookDatabase{SQLBookDatabase: db, anchor: anchor}, nil
+}
+
+// sqliteBookDatabase wraps SQLBookDatabase to additionally close the anchor
+// connection newSQLiteDB opens for ":memory:" databases.
+type sqliteBookDatabase struct {
+	*SQLBookDatabase
+	anchor *sql.DB
+}
+
+func (db *sqliteBookDatabase) Close() {
+	db.SQLBookDatabase.Close()
+	db.anchor.Close()
+}